@@ -13,6 +13,8 @@ Client 是 RPC 框架的客户端实现。
 package miniRPC
 
 import (
+	"bufio"
+	"context"
 	"encoding/json"
 	"errors"
 	"fmt"
@@ -20,7 +22,10 @@ import (
 	"log"
 	"miniRPC/codec"
 	"net"
+	"net/http"
+	"strings"
 	"sync"
+	"time"
 )
 
 // Call 表示一个正在进行的 RPC 调用
@@ -218,9 +223,19 @@ func (client *Client) Go(serviceMethod string, args, reply interface{}, done cha
 
 // Call 同步调用，等待调用完成并返回错误信息
 func (client *Client) Call(serviceMethod string, args, reply interface{}) error {
-	// 使用 Go 方法发起异步调用，然后等待调用完成
-	call := <-client.Go(serviceMethod, args, reply, make(chan *Call, 1)).Done
-	return call.Error // 返回调用的错误信息
+	return client.CallContext(context.Background(), serviceMethod, args, reply)
+}
+
+// CallContext 和 Call 类似，但支持通过 ctx 来控制调用的超时或取消
+func (client *Client) CallContext(ctx context.Context, serviceMethod string, args, reply interface{}) error {
+	call := client.Go(serviceMethod, args, reply, make(chan *Call, 1))
+	select {
+	case <-ctx.Done():
+		client.removeCall(call.Seq)
+		return ctx.Err()
+	case call := <-call.Done:
+		return call.Error
+	}
 }
 
 // parseOptions 解析客户端选项
@@ -258,8 +273,52 @@ func NewClient(conn net.Conn, opt *Option) (*Client, error) {
 		_ = conn.Close()
 		return nil, err
 	}
-	// 创建客户端实例
-	return newClientCodec(f(conn), opt), nil
+	// 创建客户端实例，按 MaxResponseSize 限制每一帧的大小
+	return newClientCodec(codec.NewFramedCodec(f, conn, opt.MaxResponseSize), opt), nil
+}
+
+// clientResult 用于在 dialTimeout 中传递 NewClient 的结果
+type clientResult struct {
+	client *Client
+	err    error
+}
+
+// newClientFunc 用于生成不同传输方式（如 TCP、HTTP）下的客户端
+type newClientFunc func(conn net.Conn, opt *Option) (client *Client, err error)
+
+// dialTimeout 建立连接并在 opt.ConnectTimeout 内完成客户端握手，
+// 超时则返回错误，避免服务端迟迟不读取 Option 时客户端永久阻塞
+func dialTimeout(f newClientFunc, network, address string, opts ...*Option) (client *Client, err error) {
+	opt, err := parseOptions(opts...)
+	if err != nil {
+		return nil, err
+	}
+	conn, err := net.DialTimeout(network, address, opt.ConnectTimeout)
+	if err != nil {
+		return nil, err
+	}
+	// 如果创建客户端失败，确保关闭连接
+	defer func() {
+		if err != nil {
+			_ = conn.Close()
+		}
+	}()
+	// 缓冲为 1：超时分支抢先返回后，握手 goroutine 仍需要能把结果发出去而不阻塞
+	ch := make(chan clientResult, 1)
+	go func() {
+		client, err := f(conn, opt)
+		ch <- clientResult{client: client, err: err}
+	}()
+	if opt.ConnectTimeout == 0 {
+		result := <-ch
+		return result.client, result.err
+	}
+	select {
+	case <-time.After(opt.ConnectTimeout):
+		return nil, fmt.Errorf("rpc client: connect timeout: expect within %s", opt.ConnectTimeout)
+	case result := <-ch:
+		return result.client, result.err
+	}
 }
 
 // newClientCodec 创建一个新的客户端编解码器
@@ -276,22 +335,44 @@ func newClientCodec(cc codec.Codec, opt *Option) *Client {
 }
 
 // Dial 连接到指定地址的 RPC 服务器
-func Dial(network, address string, opts ...*Option) (client *Client, err error) {
-	// 解析选项
-	opt, err := parseOptions(opts...)
-	if err != nil {
-		return nil, err
+func Dial(network, address string, opts ...*Option) (*Client, error) {
+	return dialTimeout(NewClient, network, address, opts...)
+}
+
+// NewHTTPClient 先通过 CONNECT 请求建立到 RPC 服务器的 HTTP 连接，
+// 再在该连接之上创建一个 Client 实例
+func NewHTTPClient(conn net.Conn, opt *Option) (*Client, error) {
+	_, _ = io.WriteString(conn, fmt.Sprintf("CONNECT %s HTTP/1.0\n\n", defaultRPCPath))
+
+	// 在切换到 RPC 协议之前，需要先读取 HTTP 的响应
+	resp, err := http.ReadResponse(bufio.NewReader(conn), &http.Request{Method: "CONNECT"})
+	if err == nil && resp.Status == connected {
+		return NewClient(conn, opt)
 	}
-	// 建立网络连接
-	conn, err := net.Dial(network, address)
-	if err != nil {
-		return nil, err
+	if err == nil {
+		err = errors.New("unexpected HTTP response: " + resp.Status)
+	}
+	return nil, err
+}
+
+// DialHTTP 连接到指定网络地址上通过 HTTP 提供的 RPC 服务
+func DialHTTP(network, address string, opts ...*Option) (*Client, error) {
+	return dialTimeout(NewHTTPClient, network, address, opts...)
+}
+
+// XDial 根据 rpcAddr 调用不同的函数来连接到 RPC 服务器
+// rpcAddr 的格式为 protocol@addr，例如 http@10.0.0.1:7001, tcp@10.0.0.1:9999, unix@/tmp/miniRPC.sock
+func XDial(rpcAddr string, opts ...*Option) (*Client, error) {
+	parts := strings.Split(rpcAddr, "@")
+	if len(parts) != 2 {
+		return nil, fmt.Errorf("rpc client err: wrong format '%s', expect protocol@addr", rpcAddr)
+	}
+	protocol, addr := parts[0], parts[1]
+	switch protocol {
+	case "http":
+		return DialHTTP("tcp", addr, opts...)
+	default:
+		// tcp, unix 或其他传输协议
+		return Dial(protocol, addr, opts...)
 	}
-	// 如果创建客户端失败，确保关闭连接
-	defer func() {
-		if err != nil {
-			_ = conn.Close()
-		}
-	}()
-	return NewClient(conn, opt)
 }
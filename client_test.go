@@ -0,0 +1,50 @@
+package miniRPC
+
+import (
+	"miniRPC/codec"
+	"net"
+	"testing"
+	"time"
+)
+
+// startCodecTestServer 启动一个注册了 Foo 服务的测试服务器
+func startCodecTestServer(t *testing.T, addr chan string) {
+	var foo Foo
+	server := NewServer()
+	if err := server.Register(&foo); err != nil {
+		t.Errorf("register error: %v", err)
+		return
+	}
+	l, err := net.Listen("tcp", ":0")
+	if err != nil {
+		t.Errorf("network error: %v", err)
+		return
+	}
+	addr <- l.Addr().String()
+	server.Accept(l)
+}
+
+// TestClient_Call_CodecParity 验证同一个服务在仅切换 Option.CodecType 的情况下，
+// Gob 和 JSON 两种编解码器都能正确完成一次调用
+func TestClient_Call_CodecParity(t *testing.T) {
+	addrCh := make(chan string)
+	go startCodecTestServer(t, addrCh)
+	addr := <-addrCh
+	time.Sleep(100 * time.Millisecond)
+
+	for _, codecType := range []codec.Type{codec.GobType, codec.JsonType} {
+		client, err := Dial("tcp", addr, &Option{CodecType: codecType})
+		if err != nil {
+			t.Fatalf("dial with %s failed: %v", codecType, err)
+		}
+
+		var reply int
+		if err := client.Call("Foo.Sum", Args{Num1: 3, Num2: 4}, &reply); err != nil {
+			t.Fatalf("call with %s failed: %v", codecType, err)
+		}
+		if reply != 7 {
+			t.Fatalf("with %s: expected reply 7, got %d", codecType, reply)
+		}
+		_ = client.Close()
+	}
+}
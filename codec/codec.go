@@ -31,12 +31,22 @@ type Type string
 
 const (
 	GobType  Type = "application/gob"
-	JsonType Type = "application/json" // not implemented
+	JsonType Type = "application/json"
 )
 
+// DefaultMaxMessageSize 是 Framed 在未指定大小限制时使用的默认单帧（header+body 编码后）最大字节数
+const DefaultMaxMessageSize = 4 << 20 // 4 MiB
+
 var NewCodecFuncMap map[Type]NewCodecFunc
 
 func init() {
 	NewCodecFuncMap = make(map[Type]NewCodecFunc)
 	NewCodecFuncMap[GobType] = NewGobCodec
+	NewCodecFuncMap[JsonType] = NewJsonCodec
+}
+
+// RegisterCodec 注册一个编解码器构造函数，key 为 t
+// 使用方可以借此接入 msgpack、protobuf 等自定义编解码器，而无需修改本包
+func RegisterCodec(t Type, f NewCodecFunc) {
+	NewCodecFuncMap[t] = f
 }
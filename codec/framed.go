@@ -0,0 +1,147 @@
+/*
+	Framed 为任意 NewCodecFunc 构造的编解码器套上一层长度前缀帧格式：
+	[uvarint frameLen][frame bytes]，frame bytes 是底层编解码器对 header+body 的完整编码结果。
+	这样单次解码错误不会破坏后续消息的边界，也能在调用方不再关心某个响应体时
+	安全地跳过它而不必理解其编码格式。
+*/
+
+package codec
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+)
+
+// Framed 把消息分帧，内部每一帧仍然交给 newCodec 构造出的编解码器去编解码
+type Framed struct {
+	r            *bufio.Reader
+	w            io.Writer
+	closer       io.Closer
+	newCodec     NewCodecFunc
+	maxFrameSize int64
+	pendingCodec Codec // ReadHeader 解码出的编解码器，ReadBody 从中接着解码同一帧的 body
+}
+
+var _ Codec = (*Framed)(nil)
+
+// NewFramedCodec 用 newCodec 构造一个按帧读写的 Codec
+// maxFrameSize 是允许的单帧（header+body 编码后）最大字节数，<= 0 时沿用内部默认值
+func NewFramedCodec(newCodec NewCodecFunc, conn io.ReadWriteCloser, maxFrameSize int64) Codec {
+	if maxFrameSize <= 0 {
+		maxFrameSize = DefaultMaxMessageSize
+	}
+	return &Framed{
+		r:            bufio.NewReader(conn),
+		w:            conn,
+		closer:       conn,
+		newCodec:     newCodec,
+		maxFrameSize: maxFrameSize,
+	}
+}
+
+// ReadHeader 读取一帧完整内容到内存，再从中解码出请求头；
+// 解码器保留在 f.pendingCodec 中，供随后的 ReadBody 接着解码同一帧里的 body
+func (f *Framed) ReadHeader(h *Header) error {
+	frameBuf, err := readFrame(f.r, f.maxFrameSize)
+	if err != nil {
+		return err
+	}
+	cc := f.newCodec(newByteFrame(frameBuf))
+	if err := cc.ReadHeader(h); err != nil {
+		_ = cc.Close()
+		return err
+	}
+	f.pendingCodec = cc
+	return nil
+}
+
+// ReadBody 接着 ReadHeader 留下的解码器读取 body；body 为 nil 时直接丢弃而不解码——
+// 因为整帧早已被 ReadHeader 完整读入内存，丢弃并不需要再从连接上跳过任何字节
+func (f *Framed) ReadBody(body interface{}) error {
+	cc := f.pendingCodec
+	f.pendingCodec = nil
+	if cc == nil {
+		return errors.New("rpc codec: ReadBody called before ReadHeader")
+	}
+	defer func() { _ = cc.Close() }()
+	if body == nil {
+		return nil
+	}
+	return cc.ReadBody(body)
+}
+
+// Write 把 header 和 body 一起编码进一个内存缓冲区，再整体加上长度前缀写到连接上。
+// 这里不依赖底层编解码器内部 bufio.Writer 具体会触发几次 Write 调用——
+// 无论它因为内部缓冲区大小（例如大 body 或首次出现的 gob 类型描述）拆成多少次底层写入，
+// 最终都被完整收集进同一个 bytes.Buffer
+func (f *Framed) Write(h *Header, body interface{}) error {
+	buf := new(bytes.Buffer)
+	cc := f.newCodec(bufWriteCloser{buf})
+	err := cc.Write(h, body)
+	_ = cc.Close()
+	if err != nil {
+		return err
+	}
+	if int64(buf.Len()) > f.maxFrameSize {
+		return fmt.Errorf("rpc codec: encoded message size %d exceeds limit %d", buf.Len(), f.maxFrameSize)
+	}
+	return writeFrame(f.w, buf.Bytes())
+}
+
+// Close 关闭底层连接
+func (f *Framed) Close() error {
+	return f.closer.Close()
+}
+
+// readFrame 读取一个 uvarint 长度前缀及其后续内容，maxSize 为允许的最大长度
+func readFrame(r *bufio.Reader, maxSize int64) ([]byte, error) {
+	n, err := binary.ReadUvarint(r)
+	if err != nil {
+		return nil, err
+	}
+	// 先用 uint64 比较，避免 n 超出 int64 范围时转换成负数从而绕过大小检查
+	if n > uint64(maxSize) {
+		return nil, fmt.Errorf("rpc codec: frame size %d exceeds limit %d", n, maxSize)
+	}
+	buf := make([]byte, n)
+	if _, err := io.ReadFull(r, buf); err != nil {
+		return nil, err
+	}
+	return buf, nil
+}
+
+// writeFrame 写入一个 uvarint 长度前缀，再写入 p 本身
+func writeFrame(w io.Writer, p []byte) error {
+	var lenBuf [binary.MaxVarintLen64]byte
+	n := binary.PutUvarint(lenBuf[:], uint64(len(p)))
+	if _, err := w.Write(lenBuf[:n]); err != nil {
+		return err
+	}
+	_, err := w.Write(p)
+	return err
+}
+
+// bufWriteCloser 把 *bytes.Buffer 适配成 io.ReadWriteCloser，
+// 用来在编码时把底层编解码器不论分几次写入的字节都收集到同一个缓冲区里
+type bufWriteCloser struct{ *bytes.Buffer }
+
+func (bufWriteCloser) Close() error { return nil }
+
+// byteFrame 把一段只读字节适配成 io.ReadWriteCloser，供 newCodec 解码单个帧使用
+type byteFrame struct {
+	r *bytes.Reader
+}
+
+func newByteFrame(b []byte) *byteFrame {
+	return &byteFrame{r: bytes.NewReader(b)}
+}
+
+func (b *byteFrame) Read(p []byte) (int, error) { return b.r.Read(p) }
+func (b *byteFrame) Write(p []byte) (int, error) {
+	return 0, errors.New("rpc codec: frame is read-only")
+}
+func (b *byteFrame) Close() error { return nil }
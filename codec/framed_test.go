@@ -0,0 +1,160 @@
+package codec
+
+import (
+	"bytes"
+	"encoding/binary"
+	"io"
+	"strings"
+	"testing"
+)
+
+// readOnlyRWC 把一个只读的 io.Reader 适配成 io.ReadWriteCloser
+type readOnlyRWC struct{ io.Reader }
+
+func (readOnlyRWC) Write(p []byte) (int, error) { return len(p), nil }
+func (readOnlyRWC) Close() error                { return nil }
+
+// mustBuildFrame 用 Framed.Write 生成一段完整且合法的分帧字节，供后续用例截断/破坏
+func mustBuildFrame(h *Header, body interface{}) []byte {
+	buf := new(bytes.Buffer)
+	fw := NewFramedCodec(NewGobCodec, bufWriteCloser{buf}, DefaultMaxMessageSize)
+	if err := fw.Write(h, body); err != nil {
+		panic(err)
+	}
+	return buf.Bytes()
+}
+
+// buildFrame 和 mustBuildFrame 一样，但在测试用例里失败时给出更友好的错误信息
+func buildFrame(t *testing.T, h *Header, body interface{}) []byte {
+	t.Helper()
+	buf := new(bytes.Buffer)
+	fw := NewFramedCodec(NewGobCodec, bufWriteCloser{buf}, DefaultMaxMessageSize)
+	if err := fw.Write(h, body); err != nil {
+		t.Fatalf("buildFrame: write failed: %v", err)
+	}
+	return buf.Bytes()
+}
+
+func TestFramed_RoundTrip(t *testing.T) {
+	want := &Header{ServiceMethod: "Foo.Sum", Seq: 42}
+	data := buildFrame(t, want, "hello")
+
+	fr := NewFramedCodec(NewGobCodec, readOnlyRWC{bytes.NewReader(data)}, DefaultMaxMessageSize)
+	var got Header
+	if err := fr.ReadHeader(&got); err != nil {
+		t.Fatalf("ReadHeader failed: %v", err)
+	}
+	if got != *want {
+		t.Fatalf("header mismatch: got %+v, want %+v", got, want)
+	}
+	var body string
+	if err := fr.ReadBody(&body); err != nil {
+		t.Fatalf("ReadBody failed: %v", err)
+	}
+	if body != "hello" {
+		t.Fatalf("body mismatch: got %q, want %q", body, "hello")
+	}
+}
+
+// TestFramed_RoundTrip_LargeBody 确保远超 bufio.Writer 默认内部缓冲区（4096 字节）的 body
+// 依然能被正确分帧和还原——这类大小会导致底层编解码器的 Encode 触发不止一次底层 Write 调用
+func TestFramed_RoundTrip_LargeBody(t *testing.T) {
+	want := &Header{ServiceMethod: "BigSvc.Echo", Seq: 7}
+	body := strings.Repeat("x", 8000)
+	data := buildFrame(t, want, body)
+
+	fr := NewFramedCodec(NewGobCodec, readOnlyRWC{bytes.NewReader(data)}, DefaultMaxMessageSize)
+	var got Header
+	if err := fr.ReadHeader(&got); err != nil {
+		t.Fatalf("ReadHeader failed: %v", err)
+	}
+	if got != *want {
+		t.Fatalf("header mismatch: got %+v, want %+v", got, want)
+	}
+	var gotBody string
+	if err := fr.ReadBody(&gotBody); err != nil {
+		t.Fatalf("ReadBody failed: %v", err)
+	}
+	if gotBody != body {
+		t.Fatalf("body mismatch: got %d bytes, want %d bytes", len(gotBody), len(body))
+	}
+}
+
+func TestFramed_SkipsUnwantedBody(t *testing.T) {
+	// 验证 ReadBody(nil) 能正确丢弃 body 而不报错，并且不会影响后续读取
+	data := buildFrame(t, &Header{ServiceMethod: "Foo.Sum", Seq: 1}, "discard me")
+	data = append(data, buildFrame(t, &Header{ServiceMethod: "Foo.Sum", Seq: 2}, "keep me")...)
+
+	fr := NewFramedCodec(NewGobCodec, readOnlyRWC{bytes.NewReader(data)}, DefaultMaxMessageSize)
+	var h Header
+	if err := fr.ReadHeader(&h); err != nil {
+		t.Fatalf("ReadHeader (first) failed: %v", err)
+	}
+	if err := fr.ReadBody(nil); err != nil {
+		t.Fatalf("ReadBody(nil) failed: %v", err)
+	}
+
+	if err := fr.ReadHeader(&h); err != nil {
+		t.Fatalf("ReadHeader (second) failed: %v", err)
+	}
+	var body string
+	if err := fr.ReadBody(&body); err != nil {
+		t.Fatalf("ReadBody (second) failed: %v", err)
+	}
+	if body != "keep me" {
+		t.Fatalf("body mismatch after skip: got %q", body)
+	}
+}
+
+func TestFramed_TruncatedBody(t *testing.T) {
+	data := buildFrame(t, &Header{ServiceMethod: "Foo.Sum", Seq: 1}, "hello world")
+	// 只保留到帧长度前缀之后的一部分字节，模拟连接在发送中途被截断
+	truncated := data[:len(data)-3]
+
+	fr := NewFramedCodec(NewGobCodec, readOnlyRWC{bytes.NewReader(truncated)}, DefaultMaxMessageSize)
+	var h Header
+	if err := fr.ReadHeader(&h); err == nil {
+		t.Fatalf("expected an error reading a truncated frame, got nil")
+	}
+}
+
+func TestFramed_TruncatedHeaderLength(t *testing.T) {
+	// 声称帧有 100 字节，但实际一个字节都没跟上
+	var lenBuf [binary.MaxVarintLen64]byte
+	n := binary.PutUvarint(lenBuf[:], 100)
+
+	fr := NewFramedCodec(NewGobCodec, readOnlyRWC{bytes.NewReader(lenBuf[:n])}, DefaultMaxMessageSize)
+	var h Header
+	if err := fr.ReadHeader(&h); err == nil {
+		t.Fatalf("expected an error reading a truncated frame, got nil")
+	}
+}
+
+func TestFramed_OversizedBody(t *testing.T) {
+	const maxFrame = 16
+	data := buildFrame(t, &Header{ServiceMethod: "Foo.Sum", Seq: 1}, "this body is way too long")
+
+	fr := NewFramedCodec(NewGobCodec, readOnlyRWC{bytes.NewReader(data)}, maxFrame)
+	var h Header
+	if err := fr.ReadHeader(&h); err == nil {
+		t.Fatalf("expected an error for a frame exceeding MaxRequestSize/MaxResponseSize, got nil")
+	}
+}
+
+// FuzzFramed_ReadHeader 把任意字节丢给 ReadHeader/ReadBody，
+// 要求分帧层在面对被破坏或截断的帧时只返回 error，绝不 panic 或挂起
+func FuzzFramed_ReadHeader(f *testing.F) {
+	f.Add(mustBuildFrame(&Header{ServiceMethod: "Foo.Sum", Seq: 1}, "seed"))
+	f.Add([]byte{})
+	f.Add([]byte{0xff, 0xff, 0xff, 0xff, 0x0f})
+
+	f.Fuzz(func(t *testing.T, data []byte) {
+		fr := NewFramedCodec(NewGobCodec, readOnlyRWC{bytes.NewReader(data)}, DefaultMaxMessageSize)
+		var h Header
+		if err := fr.ReadHeader(&h); err != nil {
+			return
+		}
+		var body string
+		_ = fr.ReadBody(&body)
+	})
+}
@@ -57,8 +57,6 @@ func (c *GobCodec) ReadBody(body interface{}) error {
 // body: 要编码的请求体，可以是任意类型
 func (c *GobCodec) Write(h *Header, body interface{}) (err error) {
 	defer func() {
-		// 确保缓冲区中的数据被写入连接
-		_ = c.buf.Flush()
 		// 如果发生错误，关闭连接
 		if err != nil {
 			_ = c.Close()
@@ -77,7 +75,7 @@ func (c *GobCodec) Write(h *Header, body interface{}) (err error) {
 		return err
 	}
 
-	return nil
+	return c.buf.Flush()
 }
 
 // Close 关闭连接，释放资源
@@ -0,0 +1,60 @@
+// Copyright 2009 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package miniRPC
+
+import (
+	"html/template"
+	"net/http"
+)
+
+const debugText = `<html>
+	<body>
+	<title>miniRPC Services</title>
+	{{range .}}
+	<hr>
+	Service {{.Name}}
+	<hr>
+		<table>
+		<th align=center>Method</th><th align=center>Calls</th>
+		{{range $name, $mtype := .Method}}
+			<tr>
+			<td align=left font=fixed>{{$name}}({{$mtype.ArgType}}, {{$mtype.ReplyType}}) error</td>
+			<td align=center>{{$mtype.NumCalls}}</td>
+			</tr>
+		{{end}}
+		</table>
+	{{end}}
+	</body>
+	</html>`
+
+var debug = template.Must(template.New("RPC debug").Parse(debugText))
+
+// debugHTTP 包装 Server，用于展示已注册服务的调试页面
+type debugHTTP struct {
+	*Server
+}
+
+// debugService 是展示给模板的服务信息
+type debugService struct {
+	Name   string
+	Method map[string]*methodType
+}
+
+// ServeHTTP 渲染一个列出所有已注册服务及其方法调用次数的页面
+func (server debugHTTP) ServeHTTP(w http.ResponseWriter, req *http.Request) {
+	var services []debugService
+	server.serviceMap.Range(func(namei, svci interface{}) bool {
+		svc := svci.(*service)
+		services = append(services, debugService{
+			Name:   namei.(string),
+			Method: svc.method,
+		})
+		return true
+	})
+	err := debug.Execute(w, services)
+	if err != nil {
+		_, _ = w.Write([]byte("rpc: error executing template: " + err.Error()))
+	}
+}
@@ -1,18 +1,33 @@
 package main
 
 import (
-	"encoding/json"
-	"fmt"
 	"log"
 	"miniRPC"
-	"miniRPC/codec"
 	"net"
+	"sync"
 	"time"
 )
 
+// Foo 是一个用于演示 RPC 注册与调用的示例服务
+type Foo int
+
+// Args 是 Foo.Sum 方法的入参
+type Args struct{ Num1, Num2 int }
+
+// Sum 计算 Num1 与 Num2 之和，写入 reply
+func (f Foo) Sum(args Args, reply *int) error {
+	*reply = args.Num1 + args.Num2
+	return nil
+}
+
 // startServer 启动RPC服务器
 // addr: 用于传递服务器地址的通道
 func startServer(addr chan string) {
+	var foo Foo
+	// 注册服务，供客户端通过 "Foo.Sum" 调用
+	if err := miniRPC.Register(&foo); err != nil {
+		log.Fatal("register error:", err)
+	}
 	// 选择一个空闲端口监听
 	l, err := net.Listen("tcp", ":0")
 	if err != nil {
@@ -32,35 +47,27 @@ func main() {
 	// 在新的goroutine中启动服务器
 	go startServer(addr)
 
-	// 以下代码模拟一个简单的RPC客户端
 	// 连接到服务器
-	conn, _ := net.Dial("tcp", <-addr)
+	client, _ := miniRPC.Dial("tcp", <-addr)
 	// 确保连接最终被关闭
-	defer func() { _ = conn.Close() }()
+	defer func() { _ = client.Close() }()
 
 	// 等待服务器启动
 	time.Sleep(time.Second)
 
-	// 发送Option信息到服务器
-	_ = json.NewEncoder(conn).Encode(miniRPC.DefaultOption)
-	// 创建Gob编解码器
-	cc := codec.NewGobCodec(conn)
-
-	// 发送请求并接收响应
+	// 并发发起若干次调用
+	var wg sync.WaitGroup
 	for i := 0; i < 5; i++ {
-		// 创建请求头
-		h := &codec.Header{
-			ServiceMethod: "Foo.Sum", // 调用的服务方法名
-			Seq:           uint64(i), // 请求序号
-		}
-		// 发送请求头和请求体
-		_ = cc.Write(h, fmt.Sprintf("geerpc req %d", h.Seq))
-		// 读取响应头
-		_ = cc.ReadHeader(h)
-		// 读取响应体
-		var reply string
-		_ = cc.ReadBody(&reply)
-		// 打印响应结果
-		log.Println("reply:", reply)
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			args := &Args{Num1: i, Num2: i * i}
+			var reply int
+			if err := client.Call("Foo.Sum", args, &reply); err != nil {
+				log.Fatal("call Foo.Sum error:", err)
+			}
+			log.Printf("%d + %d = %d", args.Num1, args.Num2, reply)
+		}(i)
 	}
+	wg.Wait()
 }
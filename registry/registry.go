@@ -0,0 +1,136 @@
+/*
+	registry 实现了一个简单的 RPC 注册中心：
+	- 服务实例通过 PUT 请求上报心跳（请求头 X-Minirpc-Server 携带自己的地址）
+	- 调用方通过 GET 请求获取当前存活的服务实例列表（通过响应头 X-Minirpc-Servers 返回）
+	超过 timeout 未再次上报心跳的实例会被视为已下线并从列表中移除。
+*/
+
+package registry
+
+import (
+	"log"
+	"net/http"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+const (
+	defaultPath    = "/miniRPC/registry"
+	defaultTimeout = 5 * time.Minute
+)
+
+// ServerItem 记录一个服务实例的地址以及最近一次上报心跳的时间
+type ServerItem struct {
+	Addr  string
+	start time.Time
+}
+
+// MiniRegistry 是一个最简单的注册中心
+type MiniRegistry struct {
+	timeout time.Duration // 心跳的存活时长，0 表示永不过期
+	mu      sync.Mutex    // 保护 servers
+	servers map[string]*ServerItem
+}
+
+// New 创建一个指定心跳存活时长的 MiniRegistry
+func New(timeout time.Duration) *MiniRegistry {
+	return &MiniRegistry{
+		servers: make(map[string]*ServerItem),
+		timeout: timeout,
+	}
+}
+
+// DefaultRegistry 是默认的 MiniRegistry 实例
+var DefaultRegistry = New(defaultTimeout)
+
+// putServer 记录一次心跳，已存在的实例只刷新其存活时间
+func (r *MiniRegistry) putServer(addr string) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	s := r.servers[addr]
+	if s == nil {
+		r.servers[addr] = &ServerItem{Addr: addr, start: time.Now()}
+		return
+	}
+	s.start = time.Now()
+}
+
+// aliveServers 返回当前存活的服务地址，并顺带清理已过期的实例
+func (r *MiniRegistry) aliveServers() []string {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	var alive []string
+	for addr, s := range r.servers {
+		if r.timeout == 0 || s.start.Add(r.timeout).After(time.Now()) {
+			alive = append(alive, addr)
+		} else {
+			delete(r.servers, addr)
+		}
+	}
+	sort.Strings(alive)
+	return alive
+}
+
+// ServeHTTP 处理心跳上报（PUT）与列表查询（GET）
+func (r *MiniRegistry) ServeHTTP(w http.ResponseWriter, req *http.Request) {
+	switch req.Method {
+	case "GET":
+		// 通过自定义请求头返回当前存活的服务列表
+		w.Header().Set("X-Minirpc-Servers", strings.Join(r.aliveServers(), ","))
+	case "PUT":
+		addr := req.Header.Get("X-Minirpc-Server")
+		if addr == "" {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		r.putServer(addr)
+	default:
+		w.WriteHeader(http.StatusMethodNotAllowed)
+	}
+}
+
+// HandleHTTP 在 registryPath 上为 MiniRegistry 注册 HTTP 处理器
+func (r *MiniRegistry) HandleHTTP(registryPath string) {
+	http.Handle(registryPath, r)
+}
+
+// HandleHTTP 在默认路径上为默认的 MiniRegistry 注册 HTTP 处理器
+func HandleHTTP() {
+	DefaultRegistry.HandleHTTP(defaultPath)
+}
+
+// Heartbeat 让一个服务实例定时向 registry 发送心跳，使其不从列表中过期
+// duration 为 0 时，取 defaultTimeout 减去一分钟的安全余量
+func Heartbeat(registry, addr string, duration time.Duration) {
+	if duration == 0 {
+		duration = defaultTimeout - time.Minute
+	}
+	_ = sendHeartbeat(registry, addr)
+	go func() {
+		t := time.NewTicker(duration)
+		defer t.Stop()
+		// 即使某一次心跳失败（例如 registry 临时不可达），也继续按周期重试，
+		// 而不是就此放弃后续所有心跳
+		for range t.C {
+			_ = sendHeartbeat(registry, addr)
+		}
+	}()
+}
+
+// sendHeartbeat 向 registry 发送一次心跳
+func sendHeartbeat(registry, addr string) error {
+	log.Println(addr, "send heart beat to registry", registry)
+	httpClient := &http.Client{}
+	req, err := http.NewRequest("PUT", registry, nil)
+	if err != nil {
+		return err
+	}
+	req.Header.Set("X-Minirpc-Server", addr)
+	if _, err := httpClient.Do(req); err != nil {
+		log.Println("rpc server: heart beat err:", err)
+		return err
+	}
+	return nil
+}
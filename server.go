@@ -7,13 +7,15 @@ package miniRPC
 
 import (
 	"encoding/json" // 用于 JSON 编解码
-	"fmt"           // 用于格式化输出
+	"errors"        // 用于构造错误信息
 	"io"            // 提供 I/O 原语
 	"log"           // 用于日志记录
 	"miniRPC/codec" // 自定义的编解码器包
 	"net"           // 提供网络操作能力
 	"reflect"       // 实现运行时反射
+	"strings"       // 用于拆分 ServiceMethod
 	"sync"          // 提供同步原语
+	"time"          // 用于超时控制
 )
 
 // MagicNumber 是 RPC 请求的魔数，用于标识 RPC 请求
@@ -21,18 +23,27 @@ const MagicNumber = 0x3bef5c
 
 // Option 定义了 RPC 的选项
 type Option struct {
-	MagicNumber int        // MagicNumber 标记这是一个 RPC 请求
-	CodecType   codec.Type // 客户端可以选择不同的编解码器来编码消息体
+	MagicNumber     int           // MagicNumber 标记这是一个 RPC 请求
+	CodecType       codec.Type    // 客户端可以选择不同的编解码器来编码消息体
+	ConnectTimeout  time.Duration // 连接超时时间，0 表示不限制
+	HandleTimeout   time.Duration // 处理超时时间，0 表示不限制
+	MaxRequestSize  int64         // 服务端单帧（header+body 编码后）的最大字节数，读请求和写响应都受此限制
+	MaxResponseSize int64         // 客户端单帧（header+body 编码后）的最大字节数，读响应和写请求都受此限制
 }
 
 // DefaultOption 是默认的 RPC 选项
 var DefaultOption = &Option{
-	MagicNumber: MagicNumber,
-	CodecType:   codec.GobType, // 默认使用 Gob 编码
+	MagicNumber:     MagicNumber,
+	CodecType:       codec.GobType, // 默认使用 Gob 编码
+	ConnectTimeout:  10 * time.Second,
+	MaxRequestSize:  codec.DefaultMaxMessageSize,
+	MaxResponseSize: codec.DefaultMaxMessageSize,
 }
 
 // Server 表示一个 RPC 服务器
-type Server struct{}
+type Server struct {
+	serviceMap sync.Map // 存储已注册的服务，key 是服务名，value 是 *service
+}
 
 // NewServer 创建一个新的 Server 实例
 func NewServer() *Server {
@@ -42,6 +53,54 @@ func NewServer() *Server {
 // DefaultServer 是默认的 Server 实例
 var DefaultServer = NewServer()
 
+// Register 在 server 中发布满足 RPC 调用条件的方法集合
+func (server *Server) Register(rcvr interface{}) error {
+	s := newService(rcvr)
+	if _, dup := server.serviceMap.LoadOrStore(s.name, s); dup {
+		return errors.New("rpc: service already defined: " + s.name)
+	}
+	return nil
+}
+
+// Register 在默认 server 中发布满足 RPC 调用条件的方法集合
+func Register(rcvr interface{}) error { return DefaultServer.Register(rcvr) }
+
+// RegisterName 以指定的 name 在 server 中发布服务
+func (server *Server) RegisterName(name string, rcvr interface{}) error {
+	s := newService(rcvr)
+	s.name = name
+	if _, dup := server.serviceMap.LoadOrStore(s.name, s); dup {
+		return errors.New("rpc: service already defined: " + s.name)
+	}
+	return nil
+}
+
+// RegisterName 以指定的 name 在默认 server 中发布服务
+func RegisterName(name string, rcvr interface{}) error {
+	return DefaultServer.RegisterName(name, rcvr)
+}
+
+// findService 根据 ServiceMethod 找到对应的 service 和 methodType
+func (server *Server) findService(serviceMethod string) (svc *service, mtype *methodType, err error) {
+	dot := strings.LastIndex(serviceMethod, ".")
+	if dot < 0 {
+		err = errors.New("rpc server: service/method request ill-formed: " + serviceMethod)
+		return
+	}
+	serviceName, methodName := serviceMethod[:dot], serviceMethod[dot+1:]
+	svci, ok := server.serviceMap.Load(serviceName)
+	if !ok {
+		err = errors.New("rpc server: can't find service " + serviceName)
+		return
+	}
+	svc = svci.(*service)
+	mtype = svc.method[methodName]
+	if mtype == nil {
+		err = errors.New("rpc server: can't find method " + methodName)
+	}
+	return
+}
+
 // ServeConn 在单个连接上运行服务器
 // ServeConn 会阻塞，直到客户端断开连接
 func (server *Server) ServeConn(conn io.ReadWriteCloser) {
@@ -67,15 +126,15 @@ func (server *Server) ServeConn(conn io.ReadWriteCloser) {
 		return
 	}
 
-	// 使用编解码器处理请求
-	server.serveCodec(f(conn))
+	// 使用编解码器处理请求，并按 MaxRequestSize 限制每一帧的大小
+	server.serveCodec(codec.NewFramedCodec(f, conn, opt.MaxRequestSize), &opt)
 }
 
 // invalidRequest 是发生错误时响应的占位符
 var invalidRequest = struct{}{}
 
 // serveCodec 使用指定的编解码器处理请求
-func (server *Server) serveCodec(cc codec.Codec) {
+func (server *Server) serveCodec(cc codec.Codec, opt *Option) {
 	sending := new(sync.Mutex) // 确保完整发送一个响应
 	wg := new(sync.WaitGroup)  // 等待所有请求处理完成
 
@@ -92,7 +151,7 @@ func (server *Server) serveCodec(cc codec.Codec) {
 		}
 		wg.Add(1)
 		// 并发处理请求
-		go server.handleRequest(cc, req, sending, wg)
+		go server.handleRequest(cc, req, sending, wg, opt.HandleTimeout)
 	}
 	wg.Wait()
 	_ = cc.Close()
@@ -102,6 +161,8 @@ func (server *Server) serveCodec(cc codec.Codec) {
 type request struct {
 	h            *codec.Header // 请求头
 	argv, replyv reflect.Value // 请求参数和响应值
+	mtype        *methodType   // 调用的方法
+	svc          *service      // 调用的方法所属的服务
 }
 
 // readRequestHeader 读取请求头
@@ -123,11 +184,24 @@ func (server *Server) readRequest(cc codec.Codec) (*request, error) {
 		return nil, err
 	}
 	req := &request{h: h}
-	// TODO: 目前不知道请求参数的类型
-	// 第一天：假设它是字符串类型
-	req.argv = reflect.New(reflect.TypeOf(""))
-	if err = cc.ReadBody(req.argv.Interface()); err != nil {
+	req.svc, req.mtype, err = server.findService(h.ServiceMethod)
+	if err != nil {
+		// 找不到对应的服务/方法时丢弃请求体：整帧已由 ReadHeader 读入内存，
+		// ReadBody(nil) 只是释放这一帧的解码器，不再从连接上读取字节
+		_ = cc.ReadBody(nil)
+		return req, err
+	}
+	req.argv = req.mtype.newArgv()
+	req.replyv = req.mtype.newReplyv()
+
+	// argv 可能不是指针类型，ReadBody 需要一个指针
+	argvi := req.argv.Interface()
+	if req.argv.Kind() != reflect.Ptr {
+		argvi = req.argv.Addr().Interface()
+	}
+	if err = cc.ReadBody(argvi); err != nil {
 		log.Println("rpc server: read argv err:", err)
+		return req, err
 	}
 	return req, nil
 }
@@ -142,13 +216,38 @@ func (server *Server) sendResponse(cc codec.Codec, h *codec.Header, body interfa
 }
 
 // handleRequest 处理 RPC 请求
-func (server *Server) handleRequest(cc codec.Codec, req *request, sending *sync.Mutex, wg *sync.WaitGroup) {
-	// TODO: 应该调用注册的 RPC 方法来获取正确的 replyv
-	// 第一天：只是打印 argv 并发送一个 hello 消息
+// timeout 为 0 表示不限制处理时间
+func (server *Server) handleRequest(cc codec.Codec, req *request, sending *sync.Mutex, wg *sync.WaitGroup, timeout time.Duration) {
 	defer wg.Done()
-	log.Println(req.h, req.argv.Elem())
-	req.replyv = reflect.ValueOf(fmt.Sprintf("geerpc resp %d", req.h.Seq))
-	server.sendResponse(cc, req.h, req.replyv.Interface(), sending)
+	// once 确保超时和正常完成这两条路径里只有先发生的那个会真正发送响应、
+	// 修改 req.h：后发生的那个即使完成也不会再动 req.h 或重复发送
+	var once sync.Once
+	finished := make(chan struct{})
+	go func() {
+		err := req.svc.call(req.mtype, req.argv, req.replyv)
+		once.Do(func() {
+			if err != nil {
+				req.h.Error = err.Error()
+				server.sendResponse(cc, req.h, invalidRequest, sending)
+				return
+			}
+			server.sendResponse(cc, req.h, req.replyv.Interface(), sending)
+		})
+		close(finished)
+	}()
+
+	if timeout == 0 {
+		<-finished
+		return
+	}
+	select {
+	case <-time.After(timeout):
+		once.Do(func() {
+			req.h.Error = "rpc server: request handle timeout"
+			server.sendResponse(cc, req.h, invalidRequest, sending)
+		})
+	case <-finished:
+	}
 }
 
 // Accept 接受监听器上的连接并为每个传入连接提供服务
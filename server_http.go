@@ -0,0 +1,50 @@
+// Copyright 2009 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package miniRPC
+
+import (
+	"io"
+	"log"
+	"net/http"
+)
+
+const (
+	// connected 是 CONNECT 请求成功后回复客户端的状态行
+	connected = "200 Connected to miniRPC"
+	// defaultRPCPath 是 HTTP 方式提供 RPC 服务的默认路径
+	defaultRPCPath = "/_miniRPC_"
+	// defaultDebugPath 是 RPC 服务调试页面的默认路径
+	defaultDebugPath = "/debug/miniRPC"
+)
+
+// ServeHTTP 实现了一个处理 RPC 请求的 http.Handler
+// 它只接受 CONNECT 方法，其余请求一律返回 405
+func (server *Server) ServeHTTP(w http.ResponseWriter, req *http.Request) {
+	if req.Method != "CONNECT" {
+		w.Header().Set("Content-Type", "text/plain; charset=utf-8")
+		w.WriteHeader(http.StatusMethodNotAllowed)
+		_, _ = io.WriteString(w, "405 must CONNECT\n")
+		return
+	}
+	conn, _, err := w.(http.Hijacker).Hijack()
+	if err != nil {
+		log.Print("rpc hijacking ", req.RemoteAddr, ": ", err.Error())
+		return
+	}
+	_, _ = io.WriteString(conn, "HTTP/1.0 "+connected+"\n\n")
+	server.ServeConn(conn)
+}
+
+// HandleHTTP 在 rpcPath 上注册一个处理 RPC 请求的 HTTP 处理器，
+// 并在 debugPath 上注册一个调试页面
+func (server *Server) HandleHTTP(rpcPath, debugPath string) {
+	http.Handle(rpcPath, server)
+	http.Handle(debugPath, debugHTTP{server})
+}
+
+// HandleHTTP 在默认的路径上为默认的 Server 注册 HTTP 处理器
+func HandleHTTP() {
+	DefaultServer.HandleHTTP(defaultRPCPath, defaultDebugPath)
+}
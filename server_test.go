@@ -0,0 +1,63 @@
+package miniRPC
+
+import (
+	"net"
+	"testing"
+	"time"
+)
+
+// SlowSvc 用于测试 HandleTimeout：Slow 方法会阻塞足够长的时间以触发服务端的处理超时
+type SlowSvc int
+
+// SlowArgs 是 SlowSvc.Slow 方法的入参
+type SlowArgs struct{}
+
+// Slow 故意耗时，便于测试服务端的处理超时逻辑
+func (s SlowSvc) Slow(args SlowArgs, reply *int) error {
+	time.Sleep(100 * time.Millisecond)
+	*reply = 42
+	return nil
+}
+
+// TestServer_HandleTimeoutSendsExactlyOnce 验证超时响应和正常完成响应不会同时发生：
+// 超时触发后，即使处理 goroutine 随后完成，也不应该再发送第二个响应，
+// 更不能让后续在同一连接上的调用被这个迟到的响应污染或错位
+func TestServer_HandleTimeoutSendsExactlyOnce(t *testing.T) {
+	var slow SlowSvc
+	var foo Foo
+	server := NewServer()
+	if err := server.Register(&slow); err != nil {
+		t.Fatal(err)
+	}
+	if err := server.Register(&foo); err != nil {
+		t.Fatal(err)
+	}
+	l, err := net.Listen("tcp", ":0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	go server.Accept(l)
+	time.Sleep(50 * time.Millisecond)
+
+	client, err := Dial("tcp", l.Addr().String(), &Option{HandleTimeout: 10 * time.Millisecond})
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer func() { _ = client.Close() }()
+
+	var reply int
+	if err := client.Call("SlowSvc.Slow", SlowArgs{}, &reply); err == nil {
+		t.Fatalf("expected a timeout error")
+	}
+
+	// 等待处理 goroutine 真正跑完：如果它还会发送第二个响应，就会污染/错位后续的帧
+	time.Sleep(300 * time.Millisecond)
+
+	var sum int
+	if err := client.Call("Foo.Sum", Args{Num1: 3, Num2: 4}, &sum); err != nil {
+		t.Fatalf("subsequent call should succeed without being contaminated by a stray response: %v", err)
+	}
+	if sum != 7 {
+		t.Fatalf("expected reply 7, got %d", sum)
+	}
+}
@@ -0,0 +1,101 @@
+package miniRPC
+
+import (
+	"fmt"
+	"reflect"
+	"testing"
+)
+
+// Foo 是一个用于测试 newService/Register 的示例服务
+type Foo int
+
+// Args 是 Foo.Sum 方法的入参
+type Args struct{ Num1, Num2 int }
+
+// Sum 是一个满足条件的导出方法，应当被注册
+func (f Foo) Sum(args Args, reply *int) error {
+	*reply = args.Num1 + args.Num2
+	return nil
+}
+
+// sum 是未导出方法，不应当被注册
+func (f Foo) sum(args Args, reply *int) error {
+	*reply = args.Num1 + args.Num2
+	return nil
+}
+
+// BadArity 参数个数不对（反射算上接收者只有两个入参，而非三个），不应当被注册
+func (f Foo) BadArity(args Args) error {
+	return nil
+}
+
+// BadReturn 返回值不是唯一的 error，不应当被注册
+func (f Foo) BadReturn(args Args, reply *int) (int, error) {
+	return 0, nil
+}
+
+// BadReplyType 的 reply 不是指针类型，不应当被注册（newReplyv 需要对指针取 Elem）
+func (f Foo) BadReplyType(args Args, reply int) error {
+	return nil
+}
+
+func _assert(condition bool, msg string, v ...interface{}) {
+	if !condition {
+		panic(fmt.Sprintf("assertion failed: "+msg, v...))
+	}
+}
+
+func TestNewService(t *testing.T) {
+	var foo Foo
+	s := newService(&foo)
+	_assert(len(s.method) == 1, "wrong service Method, expect 1, but got %d", len(s.method))
+	mType := s.method["Sum"]
+	_assert(mType != nil, "wrong Method, Sum shouldn't be nil")
+
+	for _, name := range []string{"sum", "BadArity", "BadReturn", "BadReplyType"} {
+		if _, ok := s.method[name]; ok {
+			t.Fatalf("%s should not have been registered", name)
+		}
+	}
+}
+
+func TestMethodType_Call(t *testing.T) {
+	var foo Foo
+	s := newService(&foo)
+	mType := s.method["Sum"]
+
+	argv := mType.newArgv()
+	replyv := mType.newReplyv()
+	argv.Set(reflect.ValueOf(Args{Num1: 1, Num2: 3}))
+	err := s.call(mType, argv, replyv)
+	_assert(err == nil && *replyv.Interface().(*int) == 4 && mType.NumCalls() == 1,
+		"failed to call Foo.Sum")
+}
+
+func TestServer_Register_Duplicate(t *testing.T) {
+	server := NewServer()
+	var foo Foo
+	if err := server.Register(&foo); err != nil {
+		t.Fatalf("first Register should succeed: %v", err)
+	}
+	if err := server.Register(&foo); err == nil {
+		t.Fatalf("duplicate Register should have returned an error")
+	}
+}
+
+func TestServer_FindService(t *testing.T) {
+	server := NewServer()
+	var foo Foo
+	if err := server.Register(&foo); err != nil {
+		t.Fatalf("Register failed: %v", err)
+	}
+	if _, _, err := server.findService("Foo.Sum"); err != nil {
+		t.Fatalf("expected to find Foo.Sum: %v", err)
+	}
+	if _, _, err := server.findService("Foo.NoSuchMethod"); err == nil {
+		t.Fatalf("expected an error for an unregistered method")
+	}
+	if _, _, err := server.findService("NoSuchService.Sum"); err == nil {
+		t.Fatalf("expected an error for an unregistered service")
+	}
+}
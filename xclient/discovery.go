@@ -0,0 +1,94 @@
+/*
+	discovery 定义了服务发现的抽象接口 Discovery，
+	以及一种最基础的实现 MultiServersDiscovery——直接维护一份静态的服务器地址列表，
+	支持随机选择和轮询选择两种负载均衡策略。
+*/
+
+package xclient
+
+import (
+	"errors"
+	"math"
+	"math/rand"
+	"sync"
+	"time"
+)
+
+// SelectMode 代表不同的负载均衡策略
+type SelectMode int
+
+const (
+	RandomSelect     SelectMode = iota // 随机选择一个实例
+	RoundRobinSelect                   // 轮询选择一个实例
+)
+
+// Discovery 是一个服务发现的抽象接口
+type Discovery interface {
+	Refresh() error                      // 从远端刷新服务列表
+	Update(servers []string) error       // 手动更新服务列表
+	Get(mode SelectMode) (string, error) // 根据负载均衡策略选择一个服务实例
+	GetAll() ([]string, error)           // 返回所有服务实例
+}
+
+// MultiServersDiscovery 是一种不需要注册中心、由手工维护服务器地址的服务发现实现
+type MultiServersDiscovery struct {
+	r       *rand.Rand // 用于随机选择的随机数生成器
+	mu      sync.Mutex // 保护 servers 和 index
+	servers []string   // 服务器地址列表
+	index   int        // 轮询选择使用的游标
+}
+
+var _ Discovery = (*MultiServersDiscovery)(nil)
+
+// NewMultiServerDiscovery 创建一个 MultiServersDiscovery 实例
+func NewMultiServerDiscovery(servers []string) *MultiServersDiscovery {
+	d := &MultiServersDiscovery{
+		servers: servers,
+		r:       rand.New(rand.NewSource(time.Now().UnixNano())),
+	}
+	// 随机初始化一个游标，避免每次从 0 开始
+	d.index = d.r.Intn(math.MaxInt32 - 1)
+	return d
+}
+
+// Refresh 对于静态列表没有意义，直接返回 nil
+func (d *MultiServersDiscovery) Refresh() error {
+	return nil
+}
+
+// Update 手动更新服务器列表
+func (d *MultiServersDiscovery) Update(servers []string) error {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	d.servers = servers
+	return nil
+}
+
+// Get 根据 mode 选择一个服务器地址
+func (d *MultiServersDiscovery) Get(mode SelectMode) (string, error) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	n := len(d.servers)
+	if n == 0 {
+		return "", errors.New("rpc discovery: no available servers")
+	}
+	switch mode {
+	case RandomSelect:
+		return d.servers[d.r.Intn(n)], nil
+	case RoundRobinSelect:
+		s := d.servers[d.index%n] // 防止服务器列表在运行时变短导致越界
+		d.index = (d.index + 1) % n
+		return s, nil
+	default:
+		return "", errors.New("rpc discovery: not supported select mode")
+	}
+}
+
+// GetAll 返回所有服务器地址的一份拷贝
+func (d *MultiServersDiscovery) GetAll() ([]string, error) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	servers := make([]string, len(d.servers))
+	copy(servers, d.servers)
+	return servers, nil
+}
@@ -0,0 +1,89 @@
+/*
+	RegistryDiscovery 在 MultiServersDiscovery 的基础上，定期向一个 HTTP 注册中心
+	拉取当前存活的服务器列表，并缓存一段时间（TTL）以避免每次调用都去请求注册中心。
+*/
+
+package xclient
+
+import (
+	"log"
+	"net/http"
+	"strings"
+	"time"
+)
+
+// defaultUpdateTimeout 是两次向注册中心拉取列表之间的默认缓存时间
+const defaultUpdateTimeout = 10 * time.Second
+
+// RegistryDiscovery 内嵌 MultiServersDiscovery，复用其选择策略和服务器列表存储
+type RegistryDiscovery struct {
+	*MultiServersDiscovery
+	registry   string        // 注册中心地址
+	timeout    time.Duration // 服务列表的缓存时间
+	lastUpdate time.Time     // 上一次从注册中心拉取列表的时间
+}
+
+var _ Discovery = (*RegistryDiscovery)(nil)
+
+// NewRegistryDiscovery 创建一个 RegistryDiscovery 实例
+// timeout 为 0 时使用 defaultUpdateTimeout
+func NewRegistryDiscovery(registerAddr string, timeout time.Duration) *RegistryDiscovery {
+	if timeout == 0 {
+		timeout = defaultUpdateTimeout
+	}
+	return &RegistryDiscovery{
+		MultiServersDiscovery: NewMultiServerDiscovery(make([]string, 0)),
+		registry:              registerAddr,
+		timeout:               timeout,
+	}
+}
+
+// Update 手动更新服务器列表，同时刷新缓存时间
+func (d *RegistryDiscovery) Update(servers []string) error {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	d.servers = servers
+	d.lastUpdate = time.Now()
+	return nil
+}
+
+// Refresh 在缓存过期时向注册中心重新拉取服务器列表
+func (d *RegistryDiscovery) Refresh() error {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	if d.lastUpdate.Add(d.timeout).After(time.Now()) {
+		return nil
+	}
+	log.Println("rpc registry: refresh servers from registry", d.registry)
+	resp, err := http.Get(d.registry)
+	if err != nil {
+		log.Println("rpc registry: refresh err:", err)
+		return err
+	}
+	defer func() { _ = resp.Body.Close() }()
+	servers := strings.Split(resp.Header.Get("X-Minirpc-Servers"), ",")
+	d.servers = make([]string, 0, len(servers))
+	for _, server := range servers {
+		if server = strings.TrimSpace(server); server != "" {
+			d.servers = append(d.servers, server)
+		}
+	}
+	d.lastUpdate = time.Now()
+	return nil
+}
+
+// Get 先确保列表是最新的，再按策略选择一个服务器地址
+func (d *RegistryDiscovery) Get(mode SelectMode) (string, error) {
+	if err := d.Refresh(); err != nil {
+		return "", err
+	}
+	return d.MultiServersDiscovery.Get(mode)
+}
+
+// GetAll 先确保列表是最新的，再返回所有服务器地址
+func (d *RegistryDiscovery) GetAll() ([]string, error) {
+	if err := d.Refresh(); err != nil {
+		return nil, err
+	}
+	return d.MultiServersDiscovery.GetAll()
+}
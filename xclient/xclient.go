@@ -0,0 +1,140 @@
+/*
+	XClient 在 Discovery 之上封装了面向集群的调用方式：
+	- Call 从 Discovery 中选择一个地址发起调用
+	- Broadcast 向所有已发现的地址发起调用，返回第一个成功的结果，
+	  并在拿到结果后取消其余还在进行中的调用
+	为每个地址缓存一个 *miniRPC.Client，避免重复建立连接。
+*/
+
+package xclient
+
+import (
+	"context"
+	"io"
+	"miniRPC"
+	"reflect"
+	"sync"
+)
+
+// XClient 是支持负载均衡的 RPC 客户端
+type XClient struct {
+	d       Discovery
+	mode    SelectMode
+	opt     *miniRPC.Option
+	mu      sync.Mutex // 保护 clients
+	clients map[string]*miniRPC.Client
+}
+
+var _ io.Closer = (*XClient)(nil)
+
+// NewXClient 创建一个 XClient 实例
+func NewXClient(d Discovery, mode SelectMode, opt *miniRPC.Option) *XClient {
+	return &XClient{
+		d:       d,
+		mode:    mode,
+		opt:     opt,
+		clients: make(map[string]*miniRPC.Client),
+	}
+}
+
+// Close 关闭所有缓存的客户端连接
+func (xc *XClient) Close() error {
+	xc.mu.Lock()
+	defer xc.mu.Unlock()
+	for key, client := range xc.clients {
+		_ = client.Close()
+		delete(xc.clients, key)
+	}
+	return nil
+}
+
+// dial 返回 rpcAddr 对应的客户端，必要时会重新建立连接并缓存
+func (xc *XClient) dial(rpcAddr string) (*miniRPC.Client, error) {
+	xc.mu.Lock()
+	defer xc.mu.Unlock()
+	client, ok := xc.clients[rpcAddr]
+	if ok && !client.IsAvailable() {
+		_ = client.Close()
+		delete(xc.clients, rpcAddr)
+		client = nil
+	}
+	if client == nil {
+		var err error
+		client, err = miniRPC.XDial(rpcAddr, xc.opt)
+		if err != nil {
+			return nil, err
+		}
+		xc.clients[rpcAddr] = client
+	}
+	return client, nil
+}
+
+// call 向指定地址发起一次调用
+func (xc *XClient) call(rpcAddr string, ctx context.Context, serviceMethod string, args, reply interface{}) error {
+	client, err := xc.dial(rpcAddr)
+	if err != nil {
+		return err
+	}
+	return client.CallContext(ctx, serviceMethod, args, reply)
+}
+
+// Call 从 Discovery 中按负载均衡策略选择一个地址发起调用
+func (xc *XClient) Call(ctx context.Context, serviceMethod string, args, reply interface{}) error {
+	rpcAddr, err := xc.d.Get(xc.mode)
+	if err != nil {
+		return err
+	}
+	return xc.call(rpcAddr, ctx, serviceMethod, args, reply)
+}
+
+// Broadcast 向 Discovery 返回的所有地址并发发起调用
+// 任意一次调用的出错不会影响其它调用；一旦有调用成功，立即取消其余调用并返回该结果
+func (xc *XClient) Broadcast(ctx context.Context, serviceMethod string, args, reply interface{}) error {
+	servers, err := xc.d.GetAll()
+	if err != nil {
+		return err
+	}
+
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	var wg sync.WaitGroup
+	var mu sync.Mutex // 保护 succeeded 和 lastErr
+	var lastErr error
+	var succeeded bool
+
+	for _, rpcAddr := range servers {
+		wg.Add(1)
+		go func(rpcAddr string) {
+			defer wg.Done()
+			// 每个调用使用独立的 reply，避免并发写同一个 reply 造成数据竞争
+			var clonedReply interface{}
+			if reply != nil {
+				clonedReply = reflect.New(reflect.ValueOf(reply).Elem().Type()).Interface()
+			}
+			callErr := xc.call(rpcAddr, ctx, serviceMethod, args, clonedReply)
+
+			mu.Lock()
+			defer mu.Unlock()
+			if callErr != nil {
+				if lastErr == nil {
+					lastErr = callErr
+				}
+				return
+			}
+			if !succeeded {
+				if reply != nil {
+					reflect.ValueOf(reply).Elem().Set(reflect.ValueOf(clonedReply).Elem())
+				}
+				succeeded = true
+				cancel() // 已经拿到结果，取消其余还在进行中的调用
+			}
+		}(rpcAddr)
+	}
+	wg.Wait()
+
+	if succeeded {
+		return nil
+	}
+	return lastErr
+}